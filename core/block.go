@@ -0,0 +1,63 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"bytes"
+	"strings"
+)
+
+// commentBlock 表示源文件中一段连续的//注释，以及该注释在源文件中的起始行号。
+type commentBlock struct {
+	data []byte
+	line int
+}
+
+// scanCommentBlocks 从source中提取出所有包含@api标签的连续//注释块。
+//
+// 连续的//行被视为同一个块，空行或非注释行将结束当前块；只有块中
+// 至少包含一个"@api"的才会被保留下来，交给lexer作进一步解析。
+func scanCommentBlocks(source []byte) []*commentBlock {
+	blocks := []*commentBlock{}
+
+	var (
+		buf       bytes.Buffer
+		blockLine int
+		hasTag    bool
+	)
+
+	flush := func() {
+		if buf.Len() > 0 && hasTag {
+			data := make([]byte, buf.Len())
+			copy(data, buf.Bytes())
+			blocks = append(blocks, &commentBlock{data: data, line: blockLine})
+		}
+		buf.Reset()
+		hasTag = false
+	}
+
+	lines := bytes.Split(source, []byte("\n"))
+	for i, line := range lines {
+		trimmed := bytes.TrimSpace(line)
+
+		if bytes.HasPrefix(trimmed, []byte("//")) {
+			content := bytes.TrimPrefix(trimmed, []byte("//"))
+			if buf.Len() == 0 {
+				blockLine = i + 1
+			}
+			buf.Write(content)
+			buf.WriteByte('\n')
+			if strings.Contains(string(content), "@api") {
+				hasTag = true
+			}
+			continue
+		}
+
+		flush()
+	}
+	flush()
+
+	return blocks
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+// Doc 表示一段注释所解析出来的API文档内容。
+//
+// lexer.scan会不断地填充Doc的各个字段，解析结束之后，
+// Doc即可提交给output包中的各个后端转换成最终的输出格式。
+type Doc struct {
+	URL         string
+	Methods     string
+	Version     string
+	Group       string
+	Summary     string
+	Description string
+	Queries     []*Param
+	Request     *Request
+	Status      []*Status
+
+	// File是该文档来源的源文件路径，由Parser/ScanFile负责填充，
+	// serve包借此在单个文件变更时定位并替换对应的文档。
+	File string
+}
+
+// Request 表示@apiRequest所描述的请求内容。
+type Request struct {
+	Type     string
+	Headers  map[string]string
+	Params   []*Param
+	Examples []*Example
+}
+
+// Status 表示@apiStatus所描述的返回内容。
+type Status struct {
+	Code    string
+	Type    string
+	Summary string
+
+	Headers  map[string]string
+	Params   []*Param
+	Examples []*Example
+}
+
+// Param 表示@apiParam、@apiQuery等标签所描述的参数内容。
+//
+// 对于形如user.name、user.age的嵌套字段，解析之后会被归并成一棵树：
+// user是根Param，name和age作为user.Children中的子Param，Path记录了
+// 从根到当前节点的完整字段路径。
+type Param struct {
+	Name        string
+	Path        []string
+	Type        string
+	Optional    bool
+	Default     string
+	Enum        []string
+	Description string
+	Children    []*Param
+}
+
+// Example 表示@apiExample所描述的示例代码。
+type Example struct {
+	Type string
+	Code string
+}
+
+// Options 用于控制文档的解析与输出方式。
+type Options struct {
+	// Type 指定输出的文档格式，output.Generate据此选择输出后端，
+	// 可识别的取值为openapi3、swagger2、postman，其它取值会返回错误。
+	Type string
+
+	// Format 指定openapi3、swagger2等格式化输出的编码方式，取值为
+	// json或yaml，为空时默认为json。
+	Format string
+
+	// PostmanEnvironmentVars用于输出postman格式时，将@apiURL中的host部分
+	// 替换成Postman环境变量，key为host（如http://api.example.com），
+	// value为变量名（如base_url，最终渲染成{{base_url}}）。
+	PostmanEnvironmentVars map[string]string
+
+	// Concurrency 指定Parser并发扫描源文件时的worker数量，
+	// 小于等于0时采用runtime.NumCPU()。
+	Concurrency int
+
+	// Port 指定serve包内预览服务监听的端口，如":8080"。
+	Port string
+}
@@ -0,0 +1,21 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import "fmt"
+
+// SyntaxError 表示解析源码注释时遇到的语法错误，包含了出错的具体位置，
+// 方便编辑器或命令行工具直接定位到源文件。
+type SyntaxError struct {
+	File string
+	Line int
+	Col  int
+	Tag  string
+	Msg  string
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: [%s] %s", err.File, err.Line, err.Col, err.Tag, err.Msg)
+}
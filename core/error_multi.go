@@ -0,0 +1,33 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import "strings"
+
+// FileError 归并了同一个源文件在解析过程中产生的所有语法错误。
+type FileError struct {
+	File string
+	Errs []*SyntaxError
+}
+
+func (fe *FileError) Error() string {
+	msgs := make([]string, 0, len(fe.Errs))
+	for _, err := range fe.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// MultiError 聚合了一次Parser.Parse调用中，所有出错文件的FileError，
+// 使调用者可以一次性看到本次解析遇到的全部错误，而不是在第一个出错文件处中止。
+type MultiError []*FileError
+
+func (me MultiError) Error() string {
+	msgs := make([]string, 0, len(me))
+	for _, fe := range me {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
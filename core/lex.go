@@ -36,6 +36,28 @@ func (l *lexer) lineNumber() int {
 	return l.line + bytes.Count(l.data[:l.pos], []byte("\n"))
 }
 
+// 当前位置在其所在行中的列号，以1为起始值。
+func (l *lexer) columnNumber() int {
+	idx := bytes.LastIndexByte(l.data[:l.pos], '\n')
+	return l.pos - idx
+}
+
+// 以当前位置生成一个*SyntaxError，err为具体的错误描述，
+// 可以是普通的error，也可以已经是*SyntaxError（此时原样返回）。
+func (l *lexer) syntaxError(tag string, err error) *SyntaxError {
+	if se, ok := err.(*SyntaxError); ok {
+		return se
+	}
+
+	return &SyntaxError{
+		File: l.file,
+		Line: l.lineNumber(),
+		Col:  l.columnNumber(),
+		Tag:  tag,
+		Msg:  err.Error(),
+	}
+}
+
 // 获取下一个字符。
 // 可通过lexer.backup来撤消最后一次调用。
 func (l *lexer) next() rune {
@@ -52,6 +74,7 @@ func (l *lexer) next() rune {
 // 读取从当前位置到换行符|n之间的内容。首尾空格将被舍弃。
 // 可通过lexer.backup来撤消最后一次调用。
 func (l *lexer) nextLine() string {
+	l.width = 0
 	rs := []rune{}
 	for {
 		if l.pos >= len(l.data) { // 提前结束
@@ -68,9 +91,11 @@ func (l *lexer) nextLine() string {
 	}
 }
 
-// 读取当前行内，到下一个空格之间的单词。首尾空格将被舍弃。
+// 读取当前行内，到下一个空格之间的单词，会先跳过单词之前的空格
+// （但换行符视为本行提前结束，而非被跳过的空白）。
 // 可通过lexer.backup来撤消最后一次调用。
 func (l *lexer) nextWord() (str string, eol bool) {
+	l.width = 0
 	rs := []rune{}
 	for {
 		if l.pos >= len(l.data) { // 提前结束
@@ -80,6 +105,11 @@ func (l *lexer) nextWord() (str string, eol bool) {
 		r, w := utf8.DecodeRune(l.data[l.pos:])
 		l.pos += w
 		l.width += w
+
+		if len(rs) == 0 && r != '\n' && unicode.IsSpace(r) {
+			continue // 单词前的空格，跳过继续找下一个非空白字符
+		}
+
 		rs = append(rs, r)
 		if unicode.IsSpace(r) {
 			return strings.TrimSpace(string(rs)), r == '\n'
@@ -95,6 +125,8 @@ func (l *lexer) backup() {
 
 // 判断接下去的几个字符连接起来是否正好为word，若不匹配，则不移动指针。
 func (l *lexer) match(word string) bool {
+	l.width = 0
+
 	if l.pos+len(word) >= len(l.data) {
 		return false
 	}
@@ -113,44 +145,46 @@ func (l *lexer) match(word string) bool {
 	return true
 }
 
-func (l *lexer) scan() (*doc, error) {
-	d := &doc{}
-	var err error
+// scan解析l.data中的所有@apiXxx标签，填充并返回一个*Doc。
+//
+// 单个标签出错并不会中止整个文件的解析，所有遇到的错误都会被收集
+// 起来一并返回，调用者可以借此一次性看到文件中所有的语法错误。
+func (l *lexer) scan() (*Doc, []*SyntaxError) {
+	d := &Doc{}
+	var errs []*SyntaxError
 
 LOOP:
 	for {
-		switch {
-		case l.match("@apiURL"):
-			err = l.scanApiURL(d)
-		case l.match("@apiMethods"):
-			err = l.scanApiMethods(d)
-		case l.match("@apiVersion"):
-			err = l.scanApiVersion(d)
-		case l.match("@apiGroup"):
-			err = l.scanApiGroup(d)
-		case l.match("@apiQuery"):
-			err = l.scanApiQuery(d)
-		case l.match("@apiRequest"):
-			err = l.scanApiRequest(d)
-		case l.match("@apiStatus"):
-			err = l.scanApiStatus(d)
-		case l.match("@api"): // 放最后
-			err = l.scanApi(d)
-		default:
-			if eof == l.next() { // 去掉无用的字符。
-				break LOOP
-			}
+		r := l.next()
+		if r == eof {
+			break LOOP
+		}
+		if r != '@' {
+			continue LOOP
 		}
 
-		if err != nil {
-			return nil, err
+		tagsMu.RLock()
+		h, length, ok := tags.match(l.data, l.pos)
+		tagsMu.RUnlock()
+		if !ok {
+			continue LOOP
+		}
+
+		start := l.pos
+		l.pos += length
+		l.width = 0
+		tag := string(l.data[start:l.pos])
+
+		if err := h(l, d); err != nil {
+			errs = append(errs, l.syntaxError(tag, err))
 		}
 	}
 
-	return d, nil
+	d.Queries = buildParamTree(d.Queries)
+	return d, errs
 }
 
-func (l *lexer) scanApiURL(d *doc) error {
+func (l *lexer) scanApiURL(d *Doc) error {
 	str := l.nextLine()
 	if len(str) == 0 {
 		return errors.New("apiURL参数不能为空")
@@ -160,7 +194,7 @@ func (l *lexer) scanApiURL(d *doc) error {
 	return nil
 }
 
-func (l *lexer) scanApiMethods(d *doc) error {
+func (l *lexer) scanApiMethods(d *Doc) error {
 	str := l.nextLine()
 	if len(str) == 0 {
 		return errors.New("apiMethod缺少参数")
@@ -170,7 +204,7 @@ func (l *lexer) scanApiMethods(d *doc) error {
 	return nil
 }
 
-func (l *lexer) scanApiVersion(d *doc) error {
+func (l *lexer) scanApiVersion(d *Doc) error {
 	str := l.nextLine()
 	if len(str) == 0 {
 		return errors.New("apiMethod缺少参数")
@@ -180,7 +214,7 @@ func (l *lexer) scanApiVersion(d *doc) error {
 	return nil
 }
 
-func (l *lexer) scanApiGroup(d *doc) error {
+func (l *lexer) scanApiGroup(d *Doc) error {
 	str := l.nextLine()
 	if len(str) == 0 {
 		return errors.New("apiMethod缺少参数")
@@ -190,7 +224,7 @@ func (l *lexer) scanApiGroup(d *doc) error {
 	return nil
 }
 
-func (l *lexer) scanApiQuery(d *doc) error {
+func (l *lexer) scanApiQuery(d *Doc) error {
 	p, err := l.scanApiParam()
 	if err != nil {
 		return err
@@ -200,12 +234,12 @@ func (l *lexer) scanApiQuery(d *doc) error {
 	return nil
 }
 
-func (l *lexer) scanApiRequest(d *doc) error {
-	r := &request{
+func (l *lexer) scanApiRequest(d *Doc) error {
+	r := &Request{
 		Type:     l.nextLine(),
 		Headers:  map[string]string{},
-		Params:   []*param{},
-		Examples: []*example{},
+		Params:   []*Param{},
+		Examples: []*Example{},
 	}
 
 LOOP:
@@ -234,21 +268,28 @@ LOOP:
 			}
 			r.Examples = append(r.Examples, e)
 		default:
-			if eof == l.next() { // 去掉无用的字符。
+			// 以上三个子标签都不匹配，如果接下去是另一个@apiXxx标签，
+			// 说明当前块已经结束，将其留给外层scan()去处理；否则只是
+			// 注释中的普通文字，丢弃一个字符继续扫描。
+			if bytes.HasPrefix(l.data[l.pos:], []byte("@api")) {
+				break LOOP
+			}
+			if eof == l.next() {
 				break LOOP
 			}
 		}
 	}
 
+	r.Params = buildParamTree(r.Params)
 	d.Request = r
 	return nil
 }
 
-func (l *lexer) scanApiStatus(d *doc) error {
-	status := &status{
+func (l *lexer) scanApiStatus(d *Doc) error {
+	status := &Status{
 		Headers:  map[string]string{},
-		Params:   []*param{},
-		Examples: []*example{},
+		Params:   []*Param{},
+		Examples: []*Example{},
 	}
 
 	var eol bool
@@ -288,26 +329,37 @@ LOOP:
 			}
 			status.Examples = append(status.Examples, e)
 		default:
-			if eof == l.next() { // 去掉无用的字符。
+			// 以上三个子标签都不匹配，如果接下去是另一个@apiXxx标签，
+			// 说明当前块已经结束，将其留给外层scan()去处理；否则只是
+			// 注释中的普通文字，丢弃一个字符继续扫描。
+			if bytes.HasPrefix(l.data[l.pos:], []byte("@api")) {
+				break LOOP
+			}
+			if eof == l.next() {
 				break LOOP
 			}
 		}
 	}
 
+	status.Params = buildParamTree(status.Params)
+	d.Status = append(d.Status, status)
 	return nil
 }
 
-func (l *lexer) scanApiExample() (*example, error) {
-	e := &example{}
-	var eol bool
+func (l *lexer) scanApiExample() (*Example, error) {
+	e := &Example{}
 
-	e.Type, eol = l.nextWord()
-	if eol {
+	e.Type, _ = l.nextWord()
+	if len(e.Type) == 0 {
 		return nil, errors.New("@apiExample缺少参数")
 	}
 
 	e.Code = l.nextLine()
 	for {
+		if l.pos >= len(l.data) { // 示例一直延续到了文件末尾
+			break
+		}
+
 		line := l.nextLine()
 		if strings.Index(line, "@api") >= 0 {
 			l.backup()
@@ -318,29 +370,7 @@ func (l *lexer) scanApiExample() (*example, error) {
 	return e, nil
 }
 
-func (l *lexer) scanApiParam() (*param, error) {
-	p := &param{}
-	var eol bool
-	for {
-		switch {
-		case len(p.Name) == 0:
-			p.Name, eol = l.nextWord()
-		case len(p.Type) == 0:
-			p.Name, eol = l.nextWord()
-		case !p.Optional && l.match("optional"):
-			p.Optional = true
-		default:
-			p.Description = l.nextLine()
-			eol = true
-		}
-
-		if eol {
-			return p, nil
-		}
-	}
-}
-
-func (l *lexer) scanApi(d *doc) error {
+func (l *lexer) scanApi(d *Doc) error {
 	str := l.nextLine()
 	if len(str) == 0 {
 		return errors.New("api第一个参数不能为空")
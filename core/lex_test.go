@@ -0,0 +1,166 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// scanWithTimeout在独立的goroutine中运行l.scan()，超时后立即让测试失败，
+// 避免scanApiRequest/scanApiStatus/scanApiExample中的死循环回归挂起整个测试进程。
+func scanWithTimeout(t *testing.T, l *lexer) (*Doc, []*SyntaxError) {
+	t.Helper()
+
+	type result struct {
+		d    *Doc
+		errs []*SyntaxError
+	}
+	done := make(chan result, 1)
+	go func() {
+		d, errs := l.scan()
+		done <- result{d, errs}
+	}()
+
+	select {
+	case r := <-done:
+		return r.d, r.errs
+	case <-time.After(time.Second):
+		t.Fatal("l.scan()未在预期时间内返回，疑似陷入死循环")
+		return nil, nil
+	}
+}
+
+func TestRegisterTagCustom(t *testing.T) {
+	var got string
+	RegisterTag("apiDeprecated", func(l *lexer, d *Doc) error {
+		got = l.nextLine()
+		return nil
+	})
+
+	l := newLexer([]byte("@apiDeprecated use v2 instead\n"), 1, "custom.go")
+	_, errs := l.scan()
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if got != "use v2 instead" {
+		t.Errorf("got %q, want %q", got, "use v2 instead")
+	}
+}
+
+func TestScanMultiError(t *testing.T) {
+	data := []byte("line one\n@apiURL\nline two\n@apiMethods\n")
+	l := newLexer(data, 1, "multi.go")
+
+	_, errs := l.scan()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+
+	if errs[0].Tag != "apiURL" || errs[0].File != "multi.go" || errs[0].Line != 3 || errs[0].Col != 1 {
+		t.Errorf("unexpected first error: %+v", errs[0])
+	}
+	if errs[1].Tag != "apiMethods" || errs[1].Line != 5 || errs[1].Col != 1 {
+		t.Errorf("unexpected second error: %+v", errs[1])
+	}
+}
+
+func TestScanApiRequestBlock(t *testing.T) {
+	data := []byte(`@apiRequest application/json
+@apiHeader Authorization token
+@apiParam name {string} 用户名
+@apiExample json
+{"name": "test"}
+@apiURL /users
+`)
+	l := newLexer(data, 1, "request.go")
+	d, errs := scanWithTimeout(t, l)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if d.Request == nil {
+		t.Fatal("d.Request为nil")
+	}
+	if d.Request.Type != "application/json" {
+		t.Errorf("got Type %q, want %q", d.Request.Type, "application/json")
+	}
+	if d.Request.Headers["Authorization"] != "token" {
+		t.Errorf("got Header %q, want %q", d.Request.Headers["Authorization"], "token")
+	}
+	if len(d.Request.Params) != 1 || d.Request.Params[0].Name != "name" {
+		t.Errorf("unexpected Params: %+v", d.Request.Params)
+	}
+	if len(d.Request.Examples) != 1 || d.Request.Examples[0].Type != "json" {
+		t.Errorf("unexpected Examples: %+v", d.Request.Examples)
+	}
+	if d.URL != "/users" {
+		t.Errorf("got URL %q, want %q，@apiRequest之后的@apiURL未被正确识别", d.URL, "/users")
+	}
+}
+
+func TestScanApiStatusBlock(t *testing.T) {
+	data := []byte(`@apiStatus 200 application/json 请求成功
+@apiHeader X-Request-Id abc
+@apiParam id {int} 用户ID
+@apiExample json
+{"id": 1}
+@apiStatus 404 application/json 用户不存在
+`)
+	l := newLexer(data, 1, "status.go")
+	d, errs := scanWithTimeout(t, l)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if len(d.Status) != 2 {
+		t.Fatalf("got %d个Status，want 2", len(d.Status))
+	}
+
+	first := d.Status[0]
+	if first.Code != "200" || first.Headers["X-Request-Id"] != "abc" {
+		t.Errorf("unexpected first status: %+v", first)
+	}
+	if len(first.Params) != 1 || first.Params[0].Name != "id" {
+		t.Errorf("unexpected first status Params: %+v", first.Params)
+	}
+	if len(first.Examples) != 1 || first.Examples[0].Type != "json" {
+		t.Errorf("unexpected first status Examples: %+v", first.Examples)
+	}
+
+	if d.Status[1].Code != "404" {
+		t.Errorf("got second status code %q, want %q", d.Status[1].Code, "404")
+	}
+}
+
+func TestScanApiExampleAtEOF(t *testing.T) {
+	data := []byte(`@apiRequest application/json
+@apiExample json
+{"name": "test"}`)
+	l := newLexer(data, 1, "eof.go")
+	d, errs := scanWithTimeout(t, l)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if d.Request == nil || len(d.Request.Examples) != 1 {
+		t.Fatalf("unexpected Request: %+v", d.Request)
+	}
+}
+
+func TestTagTrieLongestMatch(t *testing.T) {
+	trie := newTagTrie()
+	trie.insert("api", func(l *lexer, d *Doc) error { return nil })
+	trie.insert("apiURL", func(l *lexer, d *Doc) error { return nil })
+
+	data := []byte("apiURL rest")
+	h, length, ok := trie.match(data, 0)
+	if !ok || h == nil {
+		t.Fatal("expected a match")
+	}
+	if length != len("apiURL") {
+		t.Errorf("got length %d, want %d (longest match should win over \"api\")", length, len("apiURL"))
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// scanApiParam解析@apiParam、@apiQuery等标签的内容，语法为：
+//
+//	name[.field][.field] {type} [optional] [default(<expr>)] [enum(a,b,c)] description...
+//
+// name可以是user.name这种以.分隔的嵌套字段路径；optional、default()、
+// enum()三个修饰符可以按任意顺序出现、也可以省略，它们之后的内容
+// 将整体作为description。
+func (l *lexer) scanApiParam() (*Param, error) {
+	p := &Param{}
+
+	name, eol := l.nextWord()
+	if len(name) == 0 {
+		return nil, errors.New("参数缺少名称")
+	}
+	p.Path = strings.Split(name, ".")
+	p.Name = p.Path[len(p.Path)-1]
+	if eol {
+		return nil, fmt.Errorf("参数%s缺少类型", name)
+	}
+
+	typeWord, eol := l.nextWord()
+	if len(typeWord) < 2 || typeWord[0] != '{' || typeWord[len(typeWord)-1] != '}' {
+		return nil, fmt.Errorf("参数%s的类型%q格式不正确，应以{}包裹", name, typeWord)
+	}
+	p.Type = typeWord[1 : len(typeWord)-1]
+	if len(p.Type) == 0 {
+		return nil, fmt.Errorf("参数%s缺少类型", name)
+	}
+	if eol {
+		return p, nil
+	}
+
+	for {
+		mark := l.pos
+		word, wordEOL := l.nextWord()
+
+		switch {
+		case word == "optional":
+			p.Optional = true
+		case strings.HasPrefix(word, "default(") && strings.HasSuffix(word, ")"):
+			p.Default = word[len("default(") : len(word)-1]
+		case strings.HasPrefix(word, "enum(") && strings.HasSuffix(word, ")"):
+			p.Enum = strings.Split(word[len("enum("):len(word)-1], ",")
+		default:
+			// word不是可识别的修饰符，它本身也是description的一部分，
+			// 回退到读取word之前的位置mark，将本行剩余内容整体作为description。
+			l.pos = mark
+			l.width = 0
+			p.Description = l.nextLine()
+			return p, nil
+		}
+
+		if wordEOL {
+			return p, nil
+		}
+	}
+}
+
+// ensureParamNode返回index中path对应的节点，不存在时会递归地创建
+// object类型的占位节点并挂载到其父节点的Children上，同时记录进roots。
+func ensureParamNode(path []string, index map[string]*Param, roots *[]*Param) *Param {
+	key := strings.Join(path, ".")
+	if node, ok := index[key]; ok {
+		return node
+	}
+
+	node := &Param{
+		Name: path[len(path)-1],
+		Path: append([]string{}, path...),
+		Type: "object",
+	}
+	index[key] = node
+
+	if len(path) == 1 {
+		*roots = append(*roots, node)
+	} else {
+		parent := ensureParamNode(path[:len(path)-1], index, roots)
+		parent.Children = append(parent.Children, node)
+	}
+
+	return node
+}
+
+// buildParamTree将一组解析自@apiParam/@apiQuery等标签的平级Param，
+// 按其Path重新组织成树状结构：user.name、user.age会被归并为user
+// 的两个Children，使每个输出后端都能将它们渲染为user的子字段。
+//
+// 嵌套字段的父级参数不要求显式声明，缺失时会自动补一个object类型
+// 的占位节点。
+func buildParamTree(flat []*Param) []*Param {
+	if len(flat) == 0 {
+		return flat
+	}
+
+	roots := []*Param{}
+	index := map[string]*Param{}
+
+	for _, p := range flat {
+		key := strings.Join(p.Path, ".")
+
+		if len(p.Path) == 1 {
+			index[key] = p
+			roots = append(roots, p)
+			continue
+		}
+
+		parent := ensureParamNode(p.Path[:len(p.Path)-1], index, &roots)
+		parent.Children = append(parent.Children, p)
+		index[key] = p
+	}
+
+	return roots
+}
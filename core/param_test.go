@@ -0,0 +1,101 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanApiParam(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    *Param
+		wantErr bool
+	}{
+		{
+			input: "page {int} 当前页码\n",
+			want:  &Param{Name: "page", Path: []string{"page"}, Type: "int", Description: "当前页码"},
+		},
+		{
+			input: "size {int} optional 每页数量\n",
+			want:  &Param{Name: "size", Path: []string{"size"}, Type: "int", Optional: true, Description: "每页数量"},
+		},
+		{
+			input: "sort {string} default(asc) 排序方式\n",
+			want:  &Param{Name: "sort", Path: []string{"sort"}, Type: "string", Default: "asc", Description: "排序方式"},
+		},
+		{
+			input: "state {string} enum(on,off) optional 状态\n",
+			want:  &Param{Name: "state", Path: []string{"state"}, Type: "string", Optional: true, Enum: []string{"on", "off"}, Description: "状态"},
+		},
+		{
+			input: "user.name {string} 用户名\n",
+			want:  &Param{Name: "name", Path: []string{"user", "name"}, Type: "string", Description: "用户名"},
+		},
+		{
+			input:   "page\n",
+			wantErr: true,
+		},
+		{
+			input:   "page int 当前页码\n",
+			wantErr: true,
+		},
+		{
+			input:   "page {} 当前页码\n",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		l := newLexer([]byte(c.input), 1, "param.go")
+		p, err := l.scanApiParam()
+
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got none", c.input)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(p, c.want) {
+			t.Errorf("%q: got %+v, want %+v", c.input, p, c.want)
+		}
+	}
+}
+
+func TestBuildParamTree(t *testing.T) {
+	flat := []*Param{
+		{Name: "name", Path: []string{"user", "name"}, Type: "string"},
+		{Name: "age", Path: []string{"user", "age"}, Type: "int"},
+		{Name: "id", Path: []string{"id"}, Type: "int"},
+	}
+
+	roots := buildParamTree(flat)
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 roots, got %d", len(roots))
+	}
+
+	var user *Param
+	for _, r := range roots {
+		if r.Name == "user" {
+			user = r
+		}
+	}
+	if user == nil {
+		t.Fatal("expected an auto-created \"user\" root param")
+	}
+	if user.Type != "object" {
+		t.Errorf("got type %q, want %q", user.Type, "object")
+	}
+	if len(user.Children) != 2 {
+		t.Fatalf("expected 2 children under user, got %d", len(user.Children))
+	}
+}
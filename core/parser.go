@@ -0,0 +1,201 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Parser 负责并发扫描一个项目目录下的所有源文件，提取其中的API文档。
+type Parser struct {
+	opt *Options
+}
+
+// NewParser 根据opt声明一个新的Parser实例。
+func NewParser(opt *Options) *Parser {
+	return &Parser{opt: opt}
+}
+
+// sourceFile 是生产者流向worker的一个任务单元：一个源文件中的一段注释块。
+type sourceFile struct {
+	file  string
+	block *commentBlock
+}
+
+// workerResult 是单个worker扫描完一个sourceFile之后产生的结果。
+type workerResult struct {
+	file string
+	doc  *Doc
+	errs []*SyntaxError
+}
+
+// Parse 并发扫描root目录下的所有.go源文件，返回解析出的文档列表。
+//
+// 扫描分为三个阶段：一个生产者goroutine用filepath.Walk遍历root，将
+// 每个文件中带有@api标签的注释块发送到blocks通道；opt.Concurrency个
+// worker各自独立运行一个*lexer并发消费blocks；最后由Parse本身将各
+// worker的结果合并成最终的文档列表。ctx用于随时取消整个扫描过程，
+// 单个文件的解析错误会被聚合进返回的MultiError，而不会中止其它文件
+// 的扫描。
+func (p *Parser) Parse(ctx context.Context, root string) ([]*Doc, error) {
+	concurrency := 0
+	if p.opt != nil {
+		concurrency = p.opt.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	blocks := make(chan *sourceFile)
+	results := make(chan *workerResult)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			worker(ctx, blocks, results)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	walkDone := make(chan error, 1)
+	go func() {
+		walkDone <- produce(ctx, root, blocks)
+	}()
+
+	docs := []*Doc{}
+	byFile := map[string][]*SyntaxError{}
+	order := []string{}
+
+	for r := range results {
+		if r.doc != nil {
+			docs = append(docs, r.doc)
+		}
+		if len(r.errs) > 0 {
+			if _, ok := byFile[r.file]; !ok {
+				order = append(order, r.file)
+			}
+			byFile[r.file] = append(byFile[r.file], r.errs...)
+		}
+	}
+
+	var me MultiError
+	for _, file := range order {
+		me = append(me, &FileError{File: file, Errs: byFile[file]})
+	}
+
+	if err := <-walkDone; err != nil {
+		me = append(me, &FileError{
+			File: root,
+			Errs: []*SyntaxError{{File: root, Msg: err.Error()}},
+		})
+	}
+
+	if len(me) == 0 {
+		return docs, nil
+	}
+	return docs, me
+}
+
+// produce遍历root下的所有.go文件，将其中带@api标签的注释块发送到blocks。
+// ctx被取消时立即停止遍历并返回ctx.Err()。
+func produce(ctx context.Context, root string, blocks chan<- *sourceFile) error {
+	defer close(blocks)
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range scanCommentBlocks(data) {
+			select {
+			case blocks <- &sourceFile{file: path, block: b}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	})
+}
+
+// lexBlock使用一个仅本次调用可见的*lexer解析单个注释块，并将其来源
+// 文件记录到返回的*Doc.File中。
+func lexBlock(file string, b *commentBlock) (*Doc, []*SyntaxError) {
+	l := newLexer(b.data, b.line, file)
+	d, errs := l.scan()
+	d.File = file
+	return d, errs
+}
+
+// ScanFile解析单个源文件，返回其中包含的所有文档及遇到的语法错误。
+// serve包在某个源文件发生变化时，使用该函数对其做增量的重新解析，
+// 而不必重新扫描整个项目。
+func ScanFile(path string) ([]*Doc, []*SyntaxError, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		docs []*Doc
+		errs []*SyntaxError
+	)
+	for _, b := range scanCommentBlocks(data) {
+		d, blockErrs := lexBlock(path, b)
+		docs = append(docs, d)
+		errs = append(errs, blockErrs...)
+	}
+
+	return docs, errs, nil
+}
+
+// worker从blocks中消费注释块，每个块都使用一个全新的、仅在本goroutine内
+// 使用的*lexer实例解析，worker之间不共享任何lexer状态。
+func worker(ctx context.Context, blocks <-chan *sourceFile, results chan<- *workerResult) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sf, ok := <-blocks:
+			if !ok {
+				return
+			}
+
+			d, errs := lexBlock(sf.file, sf.block)
+
+			select {
+			case results <- &workerResult{file: sf.file, doc: d, errs: errs}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// genCorpus在一个临时目录下生成count个.go文件，每个文件包含一段
+// 简单但合法的@api注释，用于衡量Parser.Parse在不同并发度下的表现。
+func genCorpus(b *testing.B, count int) string {
+	b.Helper()
+
+	dir, err := ioutil.TempDir("", "apidoc-bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { os.RemoveAll(dir) })
+
+	for i := 0; i < count; i++ {
+		content := fmt.Sprintf(`package bench
+
+// @api get /users/%d 获取用户信息
+// @apiGroup users
+// @apiVersion 1.0.0
+func handler%d() {}
+`, i, i)
+
+		path := filepath.Join(dir, fmt.Sprintf("handler%d.go", i))
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	// 额外加入一个带@apiRequest/@apiStatus/@apiExample的文件，
+	// 覆盖之前所有语料都没有触发的请求体/返回体子标签解析路径。
+	reqStatusContent := `package bench
+
+// @api post /users 创建用户
+// @apiGroup users
+// @apiRequest application/json
+// @apiHeader Authorization token
+// @apiParam name {string} 用户名
+// @apiExample json
+// {"name": "test"}
+// @apiStatus 200 application/json 创建成功
+// @apiExample json
+// {"id": 1}
+func handlerReqStatus() {}
+`
+	path := filepath.Join(dir, "handler_req_status.go")
+	if err := ioutil.WriteFile(path, []byte(reqStatusContent), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	return dir
+}
+
+func benchmarkParse(b *testing.B, fileCount, concurrency int) {
+	dir := genCorpus(b, fileCount)
+	opt := &Options{Concurrency: concurrency}
+	p := NewParser(opt)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(context.Background(), dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParse_1000Files_1Worker(b *testing.B)  { benchmarkParse(b, 1000, 1) }
+func BenchmarkParse_1000Files_4Workers(b *testing.B) { benchmarkParse(b, 1000, 4) }
+func BenchmarkParse_1000Files_8Workers(b *testing.B) { benchmarkParse(b, 1000, 8) }
+func BenchmarkParse_4000Files_8Workers(b *testing.B) { benchmarkParse(b, 4000, 8) }
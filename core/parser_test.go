@@ -0,0 +1,76 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestParseRequestStatusBlock确保Parse能在真实的并发worker下处理
+// 带@apiRequest/@apiStatus/@apiExample的源文件：此前这几个子标签的
+// 解析会死循环，worker goroutine永不返回，Parse也就永远阻塞在
+// for r := range results上，即便ctx被取消也无法退出。
+func TestParseRequestStatusBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apidoc-parse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	content := `package handler
+
+// @api post /users 创建用户
+// @apiGroup users
+// @apiRequest application/json
+// @apiHeader Authorization token
+// @apiParam name {string} 用户名
+// @apiExample json
+// {"name": "test"}
+// @apiStatus 200 application/json 创建成功
+// @apiExample json
+// {"id": 1}
+func Handler() {}
+`
+	path := filepath.Join(dir, "handler.go")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(&Options{Concurrency: 1})
+
+	done := make(chan struct{})
+	var docs []*Doc
+	var parseErr error
+	go func() {
+		docs, parseErr = p.Parse(context.Background(), dir)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Parse未在预期时间内返回，疑似worker死循环")
+	}
+
+	if parseErr != nil {
+		t.Fatalf("unexpected error: %v", parseErr)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("got %d个doc，want 1", len(docs))
+	}
+
+	d := docs[0]
+	if d.Request == nil || len(d.Request.Examples) != 1 {
+		t.Errorf("unexpected Request: %+v", d.Request)
+	}
+	if len(d.Status) != 1 || len(d.Status[0].Examples) != 1 {
+		t.Errorf("unexpected Status: %+v", d.Status)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package core
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// TagHandler 负责解析紧跟在一个@apiXxx标签之后的内容，并将结果写入d。
+// 返回的error会被lexer自动附加上文件、行号、列号等定位信息。
+type TagHandler func(l *lexer, d *Doc) error
+
+var (
+	tagsMu sync.RWMutex
+	tags   = newTagTrie()
+)
+
+// RegisterTag 注册一个@apiXxx标签的处理函数，tag不需要包含前导的@符号，
+// 比如"apiDeprecated"。第三方可借此为apidoc扩展自定义标签
+// （如@apiDeprecated、@apiPermission、@apiSchema等），而无需修改core包本身。
+//
+// 如果tag已经注册过，RegisterTag会直接覆盖原有的处理函数。
+func RegisterTag(tag string, h TagHandler) {
+	tagsMu.Lock()
+	defer tagsMu.Unlock()
+	tags.insert(tag, h)
+}
+
+func init() {
+	RegisterTag("apiURL", func(l *lexer, d *Doc) error { return l.scanApiURL(d) })
+	RegisterTag("apiMethods", func(l *lexer, d *Doc) error { return l.scanApiMethods(d) })
+	RegisterTag("apiVersion", func(l *lexer, d *Doc) error { return l.scanApiVersion(d) })
+	RegisterTag("apiGroup", func(l *lexer, d *Doc) error { return l.scanApiGroup(d) })
+	RegisterTag("apiQuery", func(l *lexer, d *Doc) error { return l.scanApiQuery(d) })
+	RegisterTag("apiRequest", func(l *lexer, d *Doc) error { return l.scanApiRequest(d) })
+	RegisterTag("apiStatus", func(l *lexer, d *Doc) error { return l.scanApiStatus(d) })
+	RegisterTag("api", func(l *lexer, d *Doc) error { return l.scanApi(d) })
+}
+
+// tagNode是tagTrie中的一个节点，按字符逐个索引已注册的标签名称。
+type tagNode struct {
+	children map[rune]*tagNode
+	handler  TagHandler // 仅在该节点正好是某个已注册标签的末尾时才非nil
+}
+
+// tagTrie以@之后的字符作为索引，用于在next()遇到@时快速判断
+// 接下来的内容是否为一个已注册的标签，避免O(n·k)的前缀扫描。
+type tagTrie struct {
+	root *tagNode
+}
+
+func newTagTrie() *tagTrie {
+	return &tagTrie{root: &tagNode{children: map[rune]*tagNode{}}}
+}
+
+func (t *tagTrie) insert(name string, h TagHandler) {
+	n := t.root
+	for _, r := range name {
+		child, found := n.children[r]
+		if !found {
+			child = &tagNode{children: map[rune]*tagNode{}}
+			n.children[r] = child
+		}
+		n = child
+	}
+	n.handler = h
+}
+
+// match在data[pos:]处尝试匹配一个已注册的标签，采用最长匹配优先，
+// 返回对应的handler及标签名称所占的字节数。未匹配到任何标签时ok为false。
+func (t *tagTrie) match(data []byte, pos int) (h TagHandler, length int, ok bool) {
+	n := t.root
+	i := pos
+
+	for i < len(data) {
+		r, w := utf8.DecodeRune(data[i:])
+		child, found := n.children[r]
+		if !found {
+			break
+		}
+
+		n = child
+		i += w
+		if n.handler != nil {
+			h = n.handler
+			length = i - pos
+			ok = true
+		}
+	}
+
+	return h, length, ok
+}
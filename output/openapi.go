@@ -0,0 +1,178 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aqlynn/apidoc/core"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OpenAPI3 将docs转换成OpenAPI 3.0格式的文档，根据opt.Format输出json或yaml。
+func OpenAPI3(docs []*core.Doc, opt *core.Options) ([]byte, error) {
+	spec := buildOpenAPI3(docs, opt)
+	return marshal(spec, opt)
+}
+
+func buildOpenAPI3(docs []*core.Doc, opt *core.Options) map[string]interface{} {
+	paths := map[string]interface{}{}
+	schemas := map[string]interface{}{}
+	version := ""
+	seq := 0
+
+	for _, d := range docs {
+		if len(d.Version) > 0 {
+			version = d.Version
+		}
+
+		path, ok := paths[d.URL].(map[string]interface{})
+		if !ok {
+			path = map[string]interface{}{}
+			paths[d.URL] = path
+		}
+
+		for _, m := range methods(d.Methods) {
+			seq++
+			path[m] = openAPI3Operation(d, schemas, seq)
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "apidoc",
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+// openAPI3Operation构建单个路径+方法对应的operation对象，请求体、
+// 响应体的schema都会以具名的方式写入schemas，operation中只保留对
+// 它们的$ref，seq用于在同一@apiGroup内出现多个接口时区分schema名称。
+func openAPI3Operation(d *core.Doc, schemas map[string]interface{}, seq int) map[string]interface{} {
+	op := map[string]interface{}{
+		"summary":     d.Summary,
+		"description": d.Description,
+		"tags":        []string{group(d)},
+		"parameters":  openAPI3Parameters(d.Queries),
+		"responses":   openAPI3Responses(d.Status, schemas, group(d), seq),
+	}
+
+	if d.Request != nil {
+		name := schemaName(group(d), "Request", fmt.Sprint(seq))
+		schemas[name] = paramsToSchema(d.Request.Params)
+		op["requestBody"] = openAPI3RequestBody(d.Request, name)
+	}
+
+	return op
+}
+
+func openAPI3Parameters(params []*core.Param) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(params))
+	for _, p := range params {
+		ret = append(ret, map[string]interface{}{
+			"name":        p.Name,
+			"in":          "query",
+			"required":    !p.Optional,
+			"description": p.Description,
+			"schema":      map[string]interface{}{"type": p.Type},
+		})
+	}
+	return ret
+}
+
+func openAPI3RequestBody(r *core.Request, schemaRefName string) map[string]interface{} {
+	mime := r.Type
+	if len(mime) == 0 {
+		mime = "application/json"
+	}
+
+	return map[string]interface{}{
+		"content": map[string]interface{}{
+			mime: map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/" + schemaRefName},
+			},
+		},
+	}
+}
+
+// openAPI3Responses为每个@apiStatus生成一个response对象，其schema
+// 以具名的方式注册到schemas中，grp、seq用于保证名称在整个文档内唯一。
+func openAPI3Responses(statuses []*core.Status, schemas map[string]interface{}, grp string, seq int) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, s := range statuses {
+		mime := s.Type
+		if len(mime) == 0 {
+			mime = "application/json"
+		}
+
+		name := schemaName(grp, "Response", s.Code, fmt.Sprint(seq))
+		schemas[name] = paramsToSchema(s.Params)
+
+		ret[s.Code] = map[string]interface{}{
+			"description": s.Summary,
+			"content": map[string]interface{}{
+				mime: map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/" + name},
+				},
+			},
+		}
+	}
+	return ret
+}
+
+// paramsToSchema 将一组Param（可能带有嵌套Children）转换成JSON Schema。
+func paramsToSchema(params []*core.Param) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for _, p := range params {
+		properties[p.Name] = paramSchema(p)
+		if !p.Optional {
+			required = append(required, p.Name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// paramSchema 返回单个Param对应的JSON Schema片段，嵌套字段递归展开。
+func paramSchema(p *core.Param) map[string]interface{} {
+	if len(p.Children) > 0 {
+		return paramsToSchema(p.Children)
+	}
+
+	schema := map[string]interface{}{
+		"type":        p.Type,
+		"description": p.Description,
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if len(p.Default) > 0 {
+		schema["default"] = p.Default
+	}
+	return schema
+}
+
+func marshal(v interface{}, opt *core.Options) ([]byte, error) {
+	if opt.Format == "yaml" {
+		return yaml.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
@@ -0,0 +1,142 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// parseDoc把data写入临时文件，通过core.ScanFile的真实词法分析流程解析，
+// 驱动端到端的core -> output各后端（OpenAPI3、Swagger2、Postman）调用，而不是直接手工构造*core.Doc。
+// 加一个超时兜底：lexer一旦回归死循环（见core#chunk0-2），测试会立刻
+// 失败而不是把整个go test挂起。
+func parseDoc(t *testing.T, data string) *core.Doc {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "apidoc-output-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "fixture.go")
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		docs []*core.Doc
+		errs []*core.SyntaxError
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		docs, errs, err := core.ScanFile(path)
+		done <- result{docs, errs, err}
+	}()
+
+	var r result
+	select {
+	case r = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("core.ScanFile未在预期时间内返回，疑似陷入死循环")
+	}
+
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if len(r.errs) != 0 {
+		t.Fatalf("unexpected syntax errors: %v", r.errs)
+	}
+	if len(r.docs) != 1 {
+		t.Fatalf("got %d个doc，want 1", len(r.docs))
+	}
+	return r.docs[0]
+}
+
+func TestOpenAPI3_RequestStatusDoc(t *testing.T) {
+	d := parseDoc(t, requestStatusFixture)
+
+	data, err := OpenAPI3([]*core.Doc{d}, &core.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("输出的不是合法JSON: %v", err)
+	}
+
+	schemas, ok := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if !ok || len(schemas) == 0 {
+		t.Fatalf("components.schemas应包含请求体/响应体的具名schema，got %v", spec["components"])
+	}
+
+	path := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})
+	post := path["post"].(map[string]interface{})
+
+	reqRef := post["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["$ref"].(string)
+	if _, ok := schemas[refName(reqRef)]; !ok {
+		t.Errorf("requestBody的$ref %q在components.schemas中找不到对应条目", reqRef)
+	}
+
+	respRef := post["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["$ref"].(string)
+	if _, ok := schemas[refName(respRef)]; !ok {
+		t.Errorf("response的$ref %q在components.schemas中找不到对应条目", respRef)
+	}
+}
+
+func TestSwagger2_RequestStatusDoc(t *testing.T) {
+	d := parseDoc(t, requestStatusFixture)
+
+	data, err := Swagger2([]*core.Doc{d}, &core.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("输出的不是合法JSON: %v", err)
+	}
+
+	definitions, ok := spec["definitions"].(map[string]interface{})
+	if !ok || len(definitions) == 0 {
+		t.Fatalf("definitions应包含请求体/响应体的具名schema，got %v", spec["definitions"])
+	}
+}
+
+func refName(ref string) string {
+	for i := len(ref) - 1; i >= 0; i-- {
+		if ref[i] == '/' {
+			return ref[i+1:]
+		}
+	}
+	return ref
+}
+
+const requestStatusFixture = `package fixture
+
+// @api 创建用户
+// @apiMethods post
+// @apiURL /users
+// @apiGroup users
+// @apiRequest application/json
+// @apiHeader Authorization token
+// @apiParam name {string} 用户名
+// @apiExample json
+// {"name": "test"}
+// @apiStatus 200 application/json 创建成功
+// @apiParam id {int} 用户ID
+// @apiExample json
+// {"id": 1}
+func Handler() {}
+`
@@ -0,0 +1,86 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package output 负责将core包解析出来的Doc列表转换成各类对外输出格式，
+// 比如OpenAPI 3.0、Swagger 2.0、Postman Collection等。
+package output
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// Generate 根据opt.Type指定的格式，将docs转换成对应的输出内容。
+func Generate(docs []*core.Doc, opt *core.Options) ([]byte, error) {
+	switch opt.Type {
+	case "openapi3":
+		return OpenAPI3(docs, opt)
+	case "swagger2":
+		return Swagger2(docs, opt)
+	case "postman":
+		return Postman(docs, opt)
+	default:
+		return nil, fmt.Errorf("不支持的输出类型：%s", opt.Type)
+	}
+}
+
+// methods 将@apiMethods中以空格或逗号分隔的请求方法拆分成列表，并转换成小写。
+func methods(str string) []string {
+	ret := []string{}
+	cur := []rune{}
+	flush := func() {
+		if len(cur) > 0 {
+			ret = append(ret, toLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	for _, r := range str {
+		switch r {
+		case ' ', '\t', ',':
+			flush()
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	return ret
+}
+
+func toLower(s string) string {
+	rs := []rune(s)
+	for i, r := range rs {
+		if r >= 'A' && r <= 'Z' {
+			rs[i] = r + ('a' - 'A')
+		}
+	}
+	return string(rs)
+}
+
+// group 返回doc所属的分组名称，未指定@apiGroup时归入default分组。
+func group(d *core.Doc) string {
+	if len(d.Group) == 0 {
+		return "default"
+	}
+	return d.Group
+}
+
+// schemaName 将parts拼接、过滤掉非字母数字的字符后，生成一个可以安全
+// 用作components.schemas/definitions键名的标识符，供openapi3、
+// swagger2两个后端为请求体、响应体注册具名schema时使用。
+func schemaName(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		for _, r := range p {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// Postman 将docs转换成Postman Collection v2.1格式的文档，按@apiGroup分组为文件夹。
+//
+// opt.PostmanEnvironmentVars用于将@apiURL中的host部分替换成形如
+// {{base_url}}的Postman环境变量，key为host，value为变量名。
+func Postman(docs []*core.Doc, opt *core.Options) ([]byte, error) {
+	folders := map[string][]interface{}{}
+	order := []string{}
+
+	for _, d := range docs {
+		g := group(d)
+		if _, ok := folders[g]; !ok {
+			order = append(order, g)
+		}
+		folders[g] = append(folders[g], postmanItem(d, opt))
+	}
+
+	items := make([]interface{}, 0, len(order))
+	for _, g := range order {
+		items = append(items, map[string]interface{}{
+			"name": g,
+			"item": folders[g],
+		})
+	}
+
+	collection := map[string]interface{}{
+		"info": map[string]interface{}{
+			"name":   "apidoc",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		"item": items,
+	}
+
+	return json.MarshalIndent(collection, "", "  ")
+}
+
+func postmanItem(d *core.Doc, opt *core.Options) map[string]interface{} {
+	// originalRequest只是Postman用来展示"这个示例响应是针对哪个请求"的
+	// 参考字段，一个@apiExample不会因为接口支持多个HTTP方法而产生多份
+	// 响应，这里统一用firstMethod代表该请求，避免按methods数量重复。
+	req := postmanRequest(d, firstMethod(d.Methods), opt)
+
+	responses := []interface{}{}
+	for _, s := range d.Status {
+		for _, e := range s.Examples {
+			responses = append(responses, map[string]interface{}{
+				"name":                    s.Summary,
+				"originalRequest":         req,
+				"status":                  s.Summary,
+				"code":                    statusCode(s.Code),
+				"header":                  postmanHeaders(s.Headers),
+				"body":                    e.Code,
+				"_postman_previewlanguage": e.Type,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"name":     d.Summary,
+		"request":  req,
+		"response": responses,
+	}
+}
+
+func postmanRequest(d *core.Doc, method string, opt *core.Options) map[string]interface{} {
+	req := map[string]interface{}{
+		"method": strings.ToUpper(method),
+		"header": []interface{}{},
+		"url":    postmanURL(d.URL, d.Queries, opt),
+	}
+
+	if d.Request != nil {
+		req["header"] = postmanHeaders(d.Request.Headers)
+		req["body"] = postmanBody(d.Request)
+	}
+
+	return req
+}
+
+// postmanBody根据Request.Type选择raw、urlencoded或formdata模式。
+func postmanBody(r *core.Request) map[string]interface{} {
+	switch {
+	case strings.Contains(r.Type, "json"), strings.Contains(r.Type, "xml"):
+		return map[string]interface{}{
+			"mode": "raw",
+			"raw":  "",
+			"options": map[string]interface{}{
+				"raw": map[string]interface{}{"language": bodyLanguage(r.Type)},
+			},
+		}
+	case strings.Contains(r.Type, "form-data"):
+		return map[string]interface{}{
+			"mode":     "formdata",
+			"formdata": postmanFormParams(r.Params),
+		}
+	default:
+		return map[string]interface{}{
+			"mode":       "urlencoded",
+			"urlencoded": postmanFormParams(r.Params),
+		}
+	}
+}
+
+func bodyLanguage(mime string) string {
+	if strings.Contains(mime, "xml") {
+		return "xml"
+	}
+	return "json"
+}
+
+func postmanFormParams(params []*core.Param) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(params))
+	for _, p := range params {
+		ret = append(ret, map[string]interface{}{
+			"key":         p.Name,
+			"value":       "",
+			"description": p.Description,
+			"disabled":    p.Optional,
+		})
+	}
+	return ret
+}
+
+func postmanHeaders(headers map[string]string) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(headers))
+	for k, v := range headers {
+		ret = append(ret, map[string]interface{}{"key": k, "value": v})
+	}
+	return ret
+}
+
+// postmanURL将@apiURL及其@apiQuery参数转换成Postman的url对象，
+// 如果opt.PostmanEnvironmentVars中存在该host的映射，则替换成{{var}}形式。
+func postmanURL(raw string, queries []*core.Param, opt *core.Options) map[string]interface{} {
+	url := raw
+	if opt != nil {
+		for host, v := range opt.PostmanEnvironmentVars {
+			if strings.HasPrefix(url, host) {
+				url = "{{" + v + "}}" + strings.TrimPrefix(url, host)
+				break
+			}
+		}
+	}
+
+	query := make([]map[string]interface{}, 0, len(queries))
+	for _, q := range queries {
+		query = append(query, map[string]interface{}{
+			"key":         q.Name,
+			"value":       "",
+			"description": q.Description,
+			"disabled":    q.Optional,
+		})
+	}
+
+	return map[string]interface{}{
+		"raw":   url,
+		"query": query,
+	}
+}
+
+func firstMethod(methodsStr string) string {
+	ms := methods(methodsStr)
+	if len(ms) == 0 {
+		return "get"
+	}
+	return ms[0]
+}
+
+func statusCode(code string) int {
+	n := 0
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
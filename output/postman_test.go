@@ -0,0 +1,58 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// TestPostman_RequestStatusDoc端到端驱动core -> output.Postman：既确认
+// 解析带@apiRequest/@apiStatus的doc不会挂起（core#chunk0-2），也确认
+// 声明了多个@apiMethods时，每个@apiExample只生成一条response，而不是
+// 按方法数量重复（core#chunk0-3）。
+func TestPostman_RequestStatusDoc(t *testing.T) {
+	d := parseDoc(t, multiMethodFixture)
+
+	data, err := Postman([]*core.Doc{d}, &core.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var collection map[string]interface{}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		t.Fatalf("输出的不是合法JSON: %v", err)
+	}
+
+	folders := collection["item"].([]interface{})
+	if len(folders) != 1 {
+		t.Fatalf("got %d个分组，want 1", len(folders))
+	}
+	items := folders[0].(map[string]interface{})["item"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("got %d个item，want 1", len(items))
+	}
+
+	responses := items[0].(map[string]interface{})["response"].([]interface{})
+	if len(responses) != 1 {
+		t.Fatalf("got %d个response，want 1（1个@apiStatus x 1个@apiExample，与@apiMethods声明的方法数无关）", len(responses))
+	}
+}
+
+const multiMethodFixture = `package fixture
+
+// @api 创建或更新用户
+// @apiMethods get,post
+// @apiURL /users
+// @apiGroup users
+// @apiRequest application/json
+// @apiStatus 200 application/json 请求成功
+// @apiParam id {int} 用户ID
+// @apiExample json
+// {"id": 1}
+func Handler() {}
+`
@@ -0,0 +1,106 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"fmt"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// Swagger2 将docs转换成Swagger 2.0格式的文档，根据opt.Format输出json或yaml。
+func Swagger2(docs []*core.Doc, opt *core.Options) ([]byte, error) {
+	spec := buildSwagger2(docs)
+	return marshal(spec, opt)
+}
+
+func buildSwagger2(docs []*core.Doc) map[string]interface{} {
+	paths := map[string]interface{}{}
+	definitions := map[string]interface{}{}
+	version := ""
+	seq := 0
+
+	for _, d := range docs {
+		if len(d.Version) > 0 {
+			version = d.Version
+		}
+
+		path, ok := paths[d.URL].(map[string]interface{})
+		if !ok {
+			path = map[string]interface{}{}
+			paths[d.URL] = path
+		}
+
+		for _, m := range methods(d.Methods) {
+			seq++
+			path[m] = swagger2Operation(d, definitions, seq)
+		}
+	}
+
+	return map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":   "apidoc",
+			"version": version,
+		},
+		"paths":       paths,
+		"definitions": definitions,
+	}
+}
+
+// swagger2Operation构建单个路径+方法对应的operation对象，请求体、
+// 响应体的schema都会以具名的方式写入definitions，operation中只保留
+// 对它们的$ref，seq用于在同一@apiGroup内出现多个接口时区分schema名称。
+func swagger2Operation(d *core.Doc, definitions map[string]interface{}, seq int) map[string]interface{} {
+	parameters := swagger2QueryParameters(d.Queries)
+	if d.Request != nil {
+		name := schemaName(group(d), "Request", fmt.Sprint(seq))
+		definitions[name] = paramsToSchema(d.Request.Params)
+		parameters = append(parameters, map[string]interface{}{
+			"name":     "body",
+			"in":       "body",
+			"required": true,
+			"schema":   map[string]interface{}{"$ref": "#/definitions/" + name},
+		})
+	}
+
+	return map[string]interface{}{
+		"summary":     d.Summary,
+		"description": d.Description,
+		"tags":        []string{group(d)},
+		"parameters":  parameters,
+		"responses":   swagger2Responses(d.Status, definitions, group(d), seq),
+	}
+}
+
+func swagger2QueryParameters(params []*core.Param) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(params))
+	for _, p := range params {
+		ret = append(ret, map[string]interface{}{
+			"name":        p.Name,
+			"in":          "query",
+			"type":        p.Type,
+			"required":    !p.Optional,
+			"description": p.Description,
+		})
+	}
+	return ret
+}
+
+// swagger2Responses为每个@apiStatus生成一个response对象，其schema
+// 以具名的方式注册到definitions中，grp、seq用于保证名称在整个文档内唯一。
+func swagger2Responses(statuses []*core.Status, definitions map[string]interface{}, grp string, seq int) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, s := range statuses {
+		name := schemaName(grp, "Response", s.Code, fmt.Sprint(seq))
+		definitions[name] = paramsToSchema(s.Params)
+
+		ret[s.Code] = map[string]interface{}{
+			"description": s.Summary,
+			"schema":      map[string]interface{}{"$ref": "#/definitions/" + name},
+		}
+	}
+	return ret
+}
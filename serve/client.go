@@ -0,0 +1,92 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package serve
+
+// indexHTML是预览页面的宿主页，加载完成后通过/api/model.json获取当前
+// 模型，并通过/ws监听invalidated事件，按分组做局部刷新。
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>apidoc preview</title>
+</head>
+<body>
+  <div id="banner" style="display:none;background:#c0392b;color:#fff;padding:8px;"></div>
+  <div id="app"></div>
+  <script>
+  (function() {
+    function renderSection(doc) {
+      var section = document.createElement("section");
+      section.dataset.group = doc.Group || "default";
+      section.innerHTML = "<h2>" + (doc.Summary || doc.URL) + "</h2><p>" + (doc.Methods || "") + " " + doc.URL + "</p>";
+      return section;
+    }
+
+    function render(model) {
+      var app = document.getElementById("app");
+      app.innerHTML = "";
+      (model.docs || []).forEach(function(doc) {
+        app.appendChild(renderSection(doc));
+      });
+    }
+
+    function showBanner(errs) {
+      var banner = document.getElementById("banner");
+      if (!errs || errs.length === 0) {
+        banner.style.display = "none";
+        banner.innerHTML = "";
+        return;
+      }
+      banner.style.display = "block";
+      banner.innerHTML = errs.map(function(e) {
+        return e.File + ":" + e.Line + ":" + e.Col + " [" + e.Tag + "] " + e.Msg;
+      }).join("<br>");
+    }
+
+    // reloadGroups只替换受影响分组对应的DOM节点，其余分组保持不变。
+    function reloadGroups(groups) {
+      fetch("/api/model.json").then(function(res) { return res.json(); }).then(function(model) {
+        showBanner(model.errs);
+
+        if (!groups || groups.length === 0) {
+          render(model);
+          return;
+        }
+
+        var docsByGroup = {};
+        (model.docs || []).forEach(function(doc) {
+          var g = doc.Group || "default";
+          (docsByGroup[g] = docsByGroup[g] || []).push(doc);
+        });
+
+        groups.forEach(function(g) {
+          document.querySelectorAll('section[data-group="' + g + '"]').forEach(function(el) {
+            el.remove();
+          });
+          (docsByGroup[g] || []).forEach(function(doc) {
+            document.getElementById("app").appendChild(renderSection(doc));
+          });
+        });
+      });
+    }
+
+    fetch("/api/model.json").then(function(res) { return res.json(); }).then(function(model) {
+      render(model);
+      showBanner(model.errs);
+    });
+
+    var ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+    ws.onmessage = function(evt) {
+      var event = JSON.parse(evt.data);
+      if (event.type === "invalidated") {
+        showBanner(event.errors);
+        reloadGroups(event.groups);
+      }
+    };
+  })();
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,94 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// hub管理所有已连接的预览页面的WebSocket连接，并向它们广播刷新事件。
+type hub struct {
+	mu      sync.Mutex
+	clients map[*websocket.Conn]bool
+	events  chan interface{}
+}
+
+func newHub() *hub {
+	return &hub{
+		clients: map[*websocket.Conn]bool{},
+		events:  make(chan interface{}, 16),
+	}
+}
+
+func (h *hub) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	h.clients[conn] = true
+	h.mu.Unlock()
+
+	go func() {
+		defer h.removeClient(conn)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func (h *hub) removeClient(conn *websocket.Conn) {
+	h.mu.Lock()
+	delete(h.clients, conn)
+	h.mu.Unlock()
+	conn.Close()
+}
+
+// broadcast将event排入发送队列，由run逐个推送给所有客户端。
+// 队列已满时直接丢弃该事件；之后的事件只刷新它自己列出的分组（groups为空时
+// 整页重绘），被丢弃事件所涉及的分组不会因此得到刷新。
+func (h *hub) broadcast(event interface{}) {
+	select {
+	case h.events <- event:
+	default:
+	}
+}
+
+func (h *hub) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-h.events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			h.mu.Lock()
+			for conn := range h.clients {
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					conn.Close()
+					delete(h.clients, conn)
+				}
+			}
+			h.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package serve 提供一个带自动刷新的本地预览服务：启动时解析一次
+// 整个项目，之后监视源文件变化，并通过WebSocket通知浏览器刷新。
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/aqlynn/apidoc/core"
+)
+
+// Server 组合了Parser、文件监视器与HTTP服务。
+type Server struct {
+	root string
+	opt  *core.Options
+
+	mu   sync.RWMutex
+	docs []*core.Doc
+	errs map[string][]*core.SyntaxError // 按文件记录的最近一次语法错误，用于banner展示
+
+	hub *hub
+}
+
+// New 声明一个监视root目录、按opt描述的方式解析与输出的Server。
+func New(root string, opt *core.Options) *Server {
+	return &Server{
+		root: root,
+		opt:  opt,
+		errs: map[string][]*core.SyntaxError{},
+		hub:  newHub(),
+	}
+}
+
+// Serve启动预览服务：先完整解析一次root下的所有文档，然后监听
+// opt.Port对外提供渲染后的文档，同时启动文件监视器，源码变化时
+// 只重新解析发生变化的文件，并通过WebSocket推送"invalidated"事件。
+// ctx被取消时，Serve会关闭HTTP服务与监视器并返回。
+func (s *Server) Serve(ctx context.Context) error {
+	docs, err := core.NewParser(s.opt).Parse(ctx, s.root)
+	if me, ok := err.(core.MultiError); ok {
+		s.recordMultiError(me)
+	} else if err != nil {
+		return err
+	}
+	s.setDocs(docs)
+
+	w, err := newWatcher(s.root, 200)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	go w.run(ctx, s.onFilesChanged)
+	go s.hub.run(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/model.json", s.handleModel)
+	mux.HandleFunc("/ws", s.hub.handleWS)
+
+	addr := s.opt.Port
+	if len(addr) == 0 {
+		addr = ":8080"
+	}
+	httpServer := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	log.Printf("apidoc preview listening on %s", addr)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) setDocs(docs []*core.Doc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs = docs
+}
+
+func (s *Server) recordMultiError(me core.MultiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fe := range me {
+		s.errs[fe.File] = fe.Errs
+	}
+}
+
+// onFilesChanged是watcher去抖之后的回调，files为本轮发生变化的源文件。
+// 解析失败的文件会保留上一次解析成功的文档，只更新其错误信息，
+// 解析成功的文件则替换掉模型中属于它的旧文档。
+func (s *Server) onFilesChanged(files []string) {
+	groups := map[string]bool{}
+
+	s.mu.Lock()
+	for _, file := range files {
+		docs, errs, err := core.ScanFile(file)
+		if err != nil {
+			// 文件已被删除或暂时不可读，保留上一次的文档不做任何变动。
+			continue
+		}
+
+		if len(errs) > 0 {
+			s.errs[file] = errs
+			continue
+		}
+		delete(s.errs, file)
+
+		s.docs = replaceDocsForFile(s.docs, file, docs)
+		for _, d := range docs {
+			groups[groupOf(d)] = true
+		}
+	}
+	errSnapshot := s.snapshotErrs()
+	s.mu.Unlock()
+
+	groupList := make([]string, 0, len(groups))
+	for g := range groups {
+		groupList = append(groupList, g)
+	}
+
+	s.hub.broadcast(invalidatedEvent{
+		Type:   "invalidated",
+		Files:  files,
+		Groups: groupList,
+		Errors: errSnapshot,
+	})
+}
+
+func (s *Server) snapshotErrs() []*core.SyntaxError {
+	all := []*core.SyntaxError{}
+	for _, errs := range s.errs {
+		all = append(all, errs...)
+	}
+	return all
+}
+
+func replaceDocsForFile(docs []*core.Doc, file string, replacement []*core.Doc) []*core.Doc {
+	kept := make([]*core.Doc, 0, len(docs)+len(replacement))
+	for _, d := range docs {
+		if d.File != file {
+			kept = append(kept, d)
+		}
+	}
+	return append(kept, replacement...)
+}
+
+func groupOf(d *core.Doc) string {
+	if len(d.Group) == 0 {
+		return "default"
+	}
+	return d.Group
+}
+
+// invalidatedEvent是通过WebSocket推送给浏览器的刷新通知。
+type invalidatedEvent struct {
+	Type   string              `json:"type"`
+	Files  []string            `json:"files"`
+	Groups []string            `json:"groups"`
+	Errors []*core.SyntaxError `json:"errors,omitempty"`
+}
+
+func (s *Server) handleModel(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"docs": s.docs,
+		"errs": s.snapshotErrs(),
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(indexHTML))
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package serve
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watcher封装了fsnotify.Watcher，负责把~200ms内的多次文件变更
+// 合并成一次回调，避免一次保存触发多次重新解析。
+type watcher struct {
+	fs       *fsnotify.Watcher
+	debounce time.Duration
+}
+
+// newWatcher创建一个递归监视root下所有目录的watcher。
+func newWatcher(root string, debounceMS int) (*watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fs.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		fs.Close()
+		return nil, err
+	}
+
+	return &watcher{
+		fs:       fs,
+		debounce: time.Duration(debounceMS) * time.Millisecond,
+	}, nil
+}
+
+func (w *watcher) Close() error {
+	return w.fs.Close()
+}
+
+// run监听文件系统事件，直到ctx被取消。所有事件都在同一个goroutine中
+// 处理，去抖计时器本身也只是一个普通的time.Timer，没有额外的锁。
+func (w *watcher) run(ctx context.Context, onChange func(files []string)) {
+	pending := map[string]bool{}
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		files := make([]string, 0, len(pending))
+		for f := range pending {
+			files = append(files, f)
+		}
+		pending = map[string]bool{}
+		onChange(files)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				flush()
+				return
+			}
+			if filepath.Ext(event.Name) != ".go" {
+				continue
+			}
+			pending[event.Name] = true
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			flush()
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			// 监视器自身的错误不应中止整个预览服务，忽略并继续监听。
+		}
+	}
+}